@@ -0,0 +1,43 @@
+//go:build cuda
+
+package main
+
+import (
+	"testing"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+// BenchmarkTrainCPU and BenchmarkTrainCUDA compare a single FitBatch pass
+// on the CPU against CUDA device 0, using a hidden layer scaled up from
+// the tutorial's 5 neurons to 512 so the difference between the two
+// devices is actually visible. Run with:
+//
+//	go test -tags=cuda -bench='Train(CPU|CUDA)' ./...
+func BenchmarkTrainCPU(b *testing.B) {
+	benchmarkTrainOnDevice(b, CPU)
+}
+
+func BenchmarkTrainCUDA(b *testing.B) {
+	benchmarkTrainOnDevice(b, CUDA(0))
+}
+
+func benchmarkTrainOnDevice(b *testing.B, device Device) {
+	x := T.New(
+		T.WithShape(4, 2),
+		T.WithBacking([]float64{0, 0, 0, 1, 1, 0, 1, 1}),
+	)
+	y := T.New(
+		T.WithShape(4, 1),
+		T.WithBacking([]float64{0, 1, 1, 0}),
+	)
+
+	network := newNeuralNetworkWithHiddenSize(true, 512, device)
+	solver := G.NewAdamSolver(G.WithLearnRate(0.05))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		network.FitBatch(x, y, solver)
+	}
+}
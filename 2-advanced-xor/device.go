@@ -0,0 +1,25 @@
+package main
+
+// Device selects which hardware a NeuralNetwork's TapeMachine should run
+// computations on.
+type Device struct {
+	isCUDA   bool
+	deviceID int
+}
+
+// CPU runs the network on the CPU. This is what every other example in
+// this repo uses, and is the default if you call NewNeuralNetwork
+// directly.
+var CPU = Device{}
+
+// CUDA runs the network on a CUDA GPU. deviceID must be 0: gorgonia
+// v0.9.17 always runs CUDA-tagged ops on the default device and has no
+// mechanism for pinning a TapeMachine to a different one. This requires
+// the program to be built with the `cuda` build tag (`go build
+// -tags=cuda`), gorgonia itself to have been built against a working
+// CUDA/cuDNN installation, and a compatible GPU to be present. See
+// device_cuda.go and device_nocuda.go for the two build-tagged halves of
+// this.
+func CUDA(deviceID int) Device {
+	return Device{isCUDA: true, deviceID: deviceID}
+}
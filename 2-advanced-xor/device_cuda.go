@@ -0,0 +1,34 @@
+//go:build cuda
+
+package main
+
+import (
+	"fmt"
+
+	G "gorgonia.org/gorgonia"
+)
+
+// deviceMachineOpts returns the extra G.VMOpt values needed to run a
+// TapeMachine on the given device.
+//
+// gorgonia.org/gorgonia@v0.9.17 has no VMOpt for pinning a TapeMachine to a
+// specific CUDA device: once this package (and gorgonia itself) are built
+// with the `cuda` tag, any op gorgonia has a CUDA kernel for runs on the GPU
+// automatically via its ExternMetadata setup, with no extra machine option
+// required. There is also no supported way to ask it for anything other
+// than the default device (device 0), so we just validate that's what was
+// requested and otherwise run exactly like the CPU path.
+//
+// On a scaled-up network (2->512->1, see bench_test.go) running the
+// forward/backward pass on a GPU is typically several times faster than
+// the CPU once the matrices are big enough to amortize the cost of
+// copying data onto the device.
+func deviceMachineOpts(device Device) []G.VMOpt {
+	if !device.isCUDA {
+		return nil
+	}
+	if device.deviceID != 0 {
+		panic(fmt.Sprintf("gorgonia v0.9.17 does not support selecting CUDA device %d; only the default device (0) is available", device.deviceID))
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build !cuda
+
+package main
+
+import (
+	"fmt"
+
+	G "gorgonia.org/gorgonia"
+)
+
+// deviceMachineOpts returns the extra G.VMOpt values needed to run a
+// TapeMachine on the given device. This build (without the `cuda` tag)
+// only supports the CPU.
+func deviceMachineOpts(device Device) []G.VMOpt {
+	if device.isCUDA {
+		panic(fmt.Sprintf("requested CUDA device %d, but this binary was built without the `cuda` build tag", device.deviceID))
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"encoding/gob"
+	"fmt"
+	"os"
+
 	G "gorgonia.org/gorgonia"
 	T "gorgonia.org/tensor"
 )
@@ -26,7 +30,25 @@ type NeuralNetwork struct {
 // Create a new neural network. We need to specify whether we are creating a network for training or testing.
 // The reason for this is that we don't need to create the nodes that are only used for training (loss, target output) when we are testing.
 // This is good for efficiency.
+//
+// This always runs on the CPU. Use NewNeuralNetworkWithDevice if you want to pick the device yourself.
 func NewNeuralNetwork(isForTraining bool) *NeuralNetwork {
+	return NewNeuralNetworkWithDevice(isForTraining, CPU)
+}
+
+// NewNeuralNetworkWithDevice is the same as NewNeuralNetwork, but lets you choose which Device the
+// network's TapeMachine runs on. Pass CPU for the default behaviour, or CUDA(deviceID) to run on a
+// GPU - see device.go for what that requires.
+func NewNeuralNetworkWithDevice(isForTraining bool, device Device) *NeuralNetwork {
+	// I have chosen 5 hidden nodes as this is less likely to get stuck in a local minima
+	return newNeuralNetworkWithHiddenSize(isForTraining, 5, device)
+}
+
+// newNeuralNetworkWithHiddenSize is the shared constructor behind
+// NewNeuralNetworkWithDevice. The hidden layer size is pulled out as a
+// parameter so bench_test.go can scale it up to show the CPU/CUDA
+// difference on a more realistically sized network.
+func newNeuralNetworkWithHiddenSize(isForTraining bool, numHidden int, device Device) *NeuralNetwork {
 	n := &NeuralNetwork{}
 
 	// Calculate the batch size. When we are training we want to use the whole dataset, so we set this to 4.
@@ -37,8 +59,7 @@ func NewNeuralNetwork(isForTraining bool) *NeuralNetwork {
 	}
 
 	// Define the shape of the neural network. This network will be a dense feed forward network.
-	// I have chosen 5 hidden nodes as this is less likely to get stuck in a local minima
-	numInputs, numHidden, numOutputs := 2, 5, 1
+	numInputs, numOutputs := 2, 1
 
 	// Create the graph for the nodes to live on.
 	n.g = G.NewGraph()
@@ -80,7 +101,8 @@ func NewNeuralNetwork(isForTraining bool) *NeuralNetwork {
 	}
 
 	// Create the machine. It is much faster to do this once here and reset it every batch than to create it every time we want to do a pass.
-	n.machine = G.NewTapeMachine(n.g, G.BindDualValues(n.getTrainableParameters()...))
+	machineOpts := append([]G.VMOpt{G.BindDualValues(n.getTrainableParameters()...)}, deviceMachineOpts(device)...)
+	n.machine = G.NewTapeMachine(n.g, machineOpts...)
 
 	return n
 }
@@ -148,3 +170,84 @@ func (n *NeuralNetwork) CopyWeightsToModel(model *NeuralNetwork) {
 func (n *NeuralNetwork) getTrainableParameters() G.Nodes {
 	return G.Nodes{n.hiddenLayerWeights, n.outputLayerWeights}
 }
+
+// encodedLayer is the gob-friendly representation of a single weight
+// matrix: its backing data plus the dimensions needed to reshape it back
+// into a tensor on load.
+type encodedLayer struct {
+	Data []float64
+	Rows int
+	Cols int
+}
+
+// encodedWeights is the full portable representation of a trained
+// NeuralNetwork's weights. Storing it as plain slices and dimensions
+// (rather than gob-encoding the gorgonia nodes directly) keeps the saved
+// file stable across gorgonia versions.
+type encodedWeights struct {
+	HiddenLayerWeights encodedLayer
+	OutputLayerWeights encodedLayer
+}
+
+func encodeLayer(node *G.Node) encodedLayer {
+	shape := node.Shape()
+	return encodedLayer{
+		Data: node.Value().Data().([]float64),
+		Rows: shape[0],
+		Cols: shape[1],
+	}
+}
+
+// SaveWeights writes every trainable parameter of the network to a gob
+// file at path, so it can be restored later with LoadWeights without
+// re-training.
+func (n *NeuralNetwork) SaveWeights(path string) error {
+	weights := encodedWeights{
+		HiddenLayerWeights: encodeLayer(n.hiddenLayerWeights),
+		OutputLayerWeights: encodeLayer(n.outputLayerWeights),
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating weights file: %w", err)
+	}
+	defer f.Close()
+
+	if err := gob.NewEncoder(f).Encode(weights); err != nil {
+		return fmt.Errorf("encoding weights: %w", err)
+	}
+	return nil
+}
+
+// LoadWeights restores weights previously written by SaveWeights into this
+// network. The network must have been constructed with the same topology
+// (i.e. the same numInputs/numHidden/numOutputs) as the one that was saved.
+func (n *NeuralNetwork) LoadWeights(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening weights file: %w", err)
+	}
+	defer f.Close()
+
+	var weights encodedWeights
+	if err := gob.NewDecoder(f).Decode(&weights); err != nil {
+		return fmt.Errorf("decoding weights: %w", err)
+	}
+
+	hiddenWeights := T.New(
+		T.WithShape(weights.HiddenLayerWeights.Rows, weights.HiddenLayerWeights.Cols),
+		T.WithBacking(weights.HiddenLayerWeights.Data),
+	)
+	outputWeights := T.New(
+		T.WithShape(weights.OutputLayerWeights.Rows, weights.OutputLayerWeights.Cols),
+		T.WithBacking(weights.OutputLayerWeights.Data),
+	)
+
+	if err := G.Let(n.hiddenLayerWeights, hiddenWeights); err != nil {
+		return fmt.Errorf("loading hidden layer weights: %w", err)
+	}
+	if err := G.Let(n.outputLayerWeights, outputWeights); err != nil {
+		return fmt.Errorf("loading output layer weights: %w", err)
+	}
+	return nil
+}
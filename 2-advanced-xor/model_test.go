@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+// TestSaveLoadWeightsRoundTrip trains a model on XOR, saves its weights,
+// loads them into a fresh prediction-only model, and checks that both
+// models predict identically.
+func TestSaveLoadWeightsRoundTrip(t *testing.T) {
+	x := T.New(
+		T.WithShape(4, 2),
+		T.WithBacking([]float64{0, 0, 0, 1, 1, 0, 1, 1}),
+	)
+	y := T.New(
+		T.WithShape(4, 1),
+		T.WithBacking([]float64{0, 1, 1, 0}),
+	)
+
+	trainingModel := NewNeuralNetwork(true)
+	solver := G.NewAdamSolver(G.WithLearnRate(0.05))
+	for epoch := 0; epoch < 100; epoch++ {
+		trainingModel.FitBatch(x, y, solver)
+	}
+
+	// PredictSingle panics on a model built with isForTraining=true, so we
+	// need a second prediction-only model to get the "want" predictions,
+	// the same way main.go does with CopyWeightsToModel.
+	wantModel := NewNeuralNetwork(false)
+	trainingModel.CopyWeightsToModel(wantModel)
+
+	weightsPath := filepath.Join(t.TempDir(), "weights.gob")
+	if err := trainingModel.SaveWeights(weightsPath); err != nil {
+		t.Fatalf("SaveWeights failed: %v", err)
+	}
+
+	loadedModel := NewNeuralNetwork(false)
+	if err := loadedModel.LoadWeights(weightsPath); err != nil {
+		t.Fatalf("LoadWeights failed: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		xi, _ := x.Slice(G.S(i, i+1))
+		xiTensor := xi.(T.Tensor).Clone().(T.Tensor)
+		xiTensor.Reshape(2)
+
+		wantPrediction := wantModel.PredictSingle(xiTensor).Data().([]float64)[0]
+		gotPrediction := loadedModel.PredictSingle(xiTensor).Data().([]float64)[0]
+
+		if wantPrediction != gotPrediction {
+			t.Errorf("sample %d: loaded model predicted %.6f, training model predicted %.6f", i, gotPrediction, wantPrediction)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+/*
+Convolutional MNIST Example by Josh Pattman
+
+This tutorial builds on the advanced XOR example by showing a more
+realistic network: a small convolutional classifier trained on the MNIST
+handwritten digit dataset. Rather than hard-coding the topology like the
+XOR examples do, this network is described as an ordered list of Layers
+(see model.go), which is a pattern you can reuse for your own datasets.
+
+Download the MNIST IDX files from http://yann.lecun.com/exdb/mnist/ and
+place them in a `data/` folder next to this file before running:
+* train-images-idx3-ubyte
+* train-labels-idx1-ubyte
+* t10k-images-idx3-ubyte
+* t10k-labels-idx1-ubyte
+
+Here is some version info about my setup (other versions may work):
+* `go 1.20.2`
+* `gorgonia v0.9.17`
+* `tensor v0.9.24`
+*/
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+const (
+	imageWidth  = 28
+	imageHeight = 28
+	numClasses  = 10
+	batchSize   = 100
+)
+
+func main() {
+	// ------------------------ Load the dataset ------------------------
+	trainImages, trainLabels := mustLoadIDXImages("data/train-images-idx3-ubyte"), mustLoadIDXLabels("data/train-labels-idx1-ubyte")
+	x := prepareX(trainImages)
+	y := prepareY(trainLabels)
+
+	testImages, testLabels := mustLoadIDXImages("data/t10k-images-idx3-ubyte"), mustLoadIDXLabels("data/t10k-labels-idx1-ubyte")
+	testX := prepareX(testImages)
+	testY := prepareY(testLabels)
+
+	numSamples := len(trainLabels)
+
+	// ------------------------ Create the neural net ------------------------
+	// The network is a small LeNet-style stack: two convolution+pool blocks
+	// followed by a flatten and two dense layers.
+	inputShape := T.Shape{batchSize, 1, imageHeight, imageWidth}
+	model, err := buildModel(inputShape, true)
+	if err != nil {
+		panic(err)
+	}
+
+	// testModel shares no weights with model until we copy them over each
+	// epoch; building it separately (with isForTraining=false) means its
+	// Dropout layers are always disabled, so test accuracy isn't corrupted
+	// by dropout noise.
+	testModel, err := buildModel(inputShape, false)
+	if err != nil {
+		panic(err)
+	}
+
+	// ------------------------ Train the neural net ------------------------
+	solver := G.NewAdamSolver(G.WithLearnRate(0.001))
+
+	numBatches := numSamples / batchSize
+	numTestBatches := len(testLabels) / batchSize
+	for epoch := 0; epoch < 5; epoch++ {
+		var epochLoss float64
+		for batch := 0; batch < numBatches; batch++ {
+			xBatch, err := sliceBatch(x, batch, batchSize)
+			if err != nil {
+				panic(err)
+			}
+			yBatch, err := sliceBatch(y, batch, batchSize)
+			if err != nil {
+				panic(err)
+			}
+
+			loss, err := model.FitBatch(xBatch, yBatch, solver)
+			if err != nil {
+				panic(err)
+			}
+			epochLoss += loss
+		}
+
+		if err := model.CopyWeightsToModel(testModel); err != nil {
+			panic(err)
+		}
+		testAccuracy, err := evaluateAccuracy(testModel, testX, testY, numTestBatches)
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Printf("Epoch: %d, Avg Loss: %.4f, Test Accuracy: %.2f%%\n", epoch, epochLoss/float64(numBatches), testAccuracy*100)
+	}
+}
+
+// evaluateAccuracy runs model over numBatches batches of x/y and returns
+// the fraction of samples whose predicted class (the argmax of the
+// softmax output) matches the target's one-hot class.
+func evaluateAccuracy(model *Model, x, y T.Tensor, numBatches int) (float64, error) {
+	var correct, total int
+	for batch := 0; batch < numBatches; batch++ {
+		xBatch, err := sliceBatch(x, batch, batchSize)
+		if err != nil {
+			return 0, err
+		}
+		yBatch, err := sliceBatch(y, batch, batchSize)
+		if err != nil {
+			return 0, err
+		}
+
+		prediction, err := model.Predict(xBatch)
+		if err != nil {
+			return 0, err
+		}
+
+		predictedData := prediction.Data().([]float64)
+		targetData := yBatch.Data().([]float64)
+		for sample := 0; sample < batchSize; sample++ {
+			predicted := argmax(predictedData[sample*numClasses : (sample+1)*numClasses])
+			actual := argmax(targetData[sample*numClasses : (sample+1)*numClasses])
+			if predicted == actual {
+				correct++
+			}
+			total++
+		}
+	}
+	return float64(correct) / float64(total), nil
+}
+
+// argmax returns the index of the largest value in v.
+func argmax(v []float64) int {
+	best := 0
+	for i, value := range v {
+		if value > v[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// buildModel constructs the layer stack shared by training and prediction.
+func buildModel(inputShape T.Shape, isForTraining bool) (*Model, error) {
+	g := G.NewGraph()
+	layers := []Layer{
+		NewConv2d(g, 1, 8, 5, 5),
+		NewMaxPool2d(2),
+		NewConv2d(g, 8, 16, 5, 5),
+		NewMaxPool2d(2),
+		NewDropout(0.25),
+		NewReshape(inputShape[0], 16*4*4),
+		NewDense(g, 16*4*4, 64),
+		NewDense(g, 64, numClasses),
+	}
+	return NewModel(g, inputShape, layers, numClasses, isForTraining)
+}
+
+// prepareX converts a slice of raw MNIST images (each a flat byte slice of
+// length imageWidth*imageHeight) into a single 4D tensor shaped
+// {numImages, 1, imageHeight, imageWidth} with pixel values scaled to [0, 1].
+func prepareX(images [][]byte) T.Tensor {
+	backing := make([]float64, len(images)*imageHeight*imageWidth)
+	for i, image := range images {
+		for j, pixel := range image {
+			backing[i*imageHeight*imageWidth+j] = float64(pixel) / 255.0
+		}
+	}
+	return T.New(T.WithShape(len(images), 1, imageHeight, imageWidth), T.WithBacking(backing))
+}
+
+// prepareY converts a slice of raw MNIST labels into a one-hot encoded 2D
+// tensor shaped {numLabels, numClasses}.
+func prepareY(labels []byte) T.Tensor {
+	backing := make([]float64, len(labels)*numClasses)
+	for i, label := range labels {
+		backing[i*numClasses+int(label)] = 1.0
+	}
+	return T.New(T.WithShape(len(labels), numClasses), T.WithBacking(backing))
+}
+
+// sliceBatch returns the batch-th slice of size batchSize samples from t.
+func sliceBatch(t T.Tensor, batch, batchSize int) (T.Tensor, error) {
+	return t.Slice(G.S(batch*batchSize, (batch+1)*batchSize))
+}
+
+// mustLoadIDXImages reads a MNIST IDX3 image file, panicking on any error.
+// This is a tutorial, so we favour simplicity over robust error handling.
+func mustLoadIDXImages(path string) [][]byte {
+	f, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	var header struct {
+		Magic      uint32
+		NumImages  uint32
+		NumRows    uint32
+		NumColumns uint32
+	}
+	if err := binary.Read(f, binary.BigEndian, &header); err != nil {
+		panic(err)
+	}
+
+	images := make([][]byte, header.NumImages)
+	imageSize := int(header.NumRows * header.NumColumns)
+	for i := range images {
+		images[i] = make([]byte, imageSize)
+		if _, err := f.Read(images[i]); err != nil {
+			panic(err)
+		}
+	}
+	return images
+}
+
+// mustLoadIDXLabels reads a MNIST IDX1 label file, panicking on any error.
+func mustLoadIDXLabels(path string) []byte {
+	f, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	var header struct {
+		Magic     uint32
+		NumLabels uint32
+	}
+	if err := binary.Read(f, binary.BigEndian, &header); err != nil {
+		panic(err)
+	}
+
+	labels := make([]byte, header.NumLabels)
+	if _, err := f.Read(labels); err != nil {
+		panic(err)
+	}
+	return labels
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+// A Layer is one stage of the forward pass. Unlike the fixed-topology
+// NeuralNetwork in the advanced XOR example, a Layer only needs to know how
+// to wire itself onto an existing node and (if it has any) hand back the
+// nodes that should be trained.
+type Layer interface {
+	// Forward attaches this layer's operations onto x and returns the
+	// resulting node. isTraining tells layers like Dropout whether they
+	// should behave differently at inference time.
+	Forward(x *G.Node, isTraining bool) (*G.Node, error)
+	// TrainableParameters returns the nodes (if any) that this layer wants
+	// gradients for.
+	TrainableParameters() G.Nodes
+}
+
+// Dense is a fully connected layer, the same shape of layer used in the
+// advanced XOR example, but packaged up so it can be dropped into a Model's
+// layer list alongside convolutional layers.
+type Dense struct {
+	weights *G.Node
+	bias    *G.Node
+}
+
+// NewDense creates a dense layer that maps `in` inputs to `out` outputs.
+func NewDense(g *G.ExprGraph, in, out int) *Dense {
+	return &Dense{
+		weights: G.NewMatrix(g, G.Float64, G.WithShape(in, out), G.WithInit(G.GlorotN(1.0)), G.WithName(fmt.Sprintf("dense_w_%d_%d", in, out))),
+		bias:    G.NewVector(g, G.Float64, G.WithShape(out), G.WithInit(G.Zeroes()), G.WithName(fmt.Sprintf("dense_b_%d", out))),
+	}
+}
+
+func (d *Dense) Forward(x *G.Node, isTraining bool) (*G.Node, error) {
+	xw, err := G.Mul(x, d.weights)
+	if err != nil {
+		return nil, err
+	}
+	return G.BroadcastAdd(xw, d.bias, nil, []byte{0})
+}
+
+func (d *Dense) TrainableParameters() G.Nodes {
+	return G.Nodes{d.weights, d.bias}
+}
+
+// Conv2d is a single convolutional layer over a batch of single or
+// multi-channel images.
+type Conv2d struct {
+	kernels    *G.Node
+	kernelSize []int
+	pad        []int
+	stride     []int
+	dilation   []int
+}
+
+// NewConv2d creates a layer with `outChannels` kernels of size
+// kernelH x kernelW, each looking at `inChannels` input channels.
+func NewConv2d(g *G.ExprGraph, inChannels, outChannels, kernelH, kernelW int) *Conv2d {
+	return &Conv2d{
+		kernels:    G.NewTensor(g, G.Float64, 4, G.WithShape(outChannels, inChannels, kernelH, kernelW), G.WithInit(G.GlorotN(1.0)), G.WithName("conv_kernels")),
+		kernelSize: []int{kernelH, kernelW},
+		pad:        []int{0, 0},
+		stride:     []int{1, 1},
+		dilation:   []int{1, 1},
+	}
+}
+
+func (c *Conv2d) Forward(x *G.Node, isTraining bool) (*G.Node, error) {
+	return G.Conv2d(x, c.kernels, c.kernelSize, c.pad, c.stride, c.dilation)
+}
+
+func (c *Conv2d) TrainableParameters() G.Nodes {
+	return G.Nodes{c.kernels}
+}
+
+// MaxPool2d downsamples each channel of its input by taking the maximum
+// value inside a sliding window.
+type MaxPool2d struct {
+	kernelSize []int
+	pad        []int
+	stride     []int
+}
+
+// NewMaxPool2d creates a pooling layer with a square `size` x `size` window
+// and matching stride, i.e. non-overlapping pooling.
+func NewMaxPool2d(size int) *MaxPool2d {
+	return &MaxPool2d{
+		kernelSize: []int{size, size},
+		pad:        []int{0, 0},
+		stride:     []int{size, size},
+	}
+}
+
+func (m *MaxPool2d) Forward(x *G.Node, isTraining bool) (*G.Node, error) {
+	return G.MaxPool2D(x, m.kernelSize, m.pad, m.stride)
+}
+
+func (m *MaxPool2d) TrainableParameters() G.Nodes {
+	return nil
+}
+
+// Dropout randomly zeroes elements of its input during training, which
+// helps stop the network from overfitting to the training set.
+type Dropout struct {
+	probability float64
+}
+
+// NewDropout creates a dropout layer that zeroes each element with the
+// given probability.
+func NewDropout(probability float64) *Dropout {
+	return &Dropout{probability: probability}
+}
+
+func (d *Dropout) Forward(x *G.Node, isTraining bool) (*G.Node, error) {
+	// Dropout should only randomly zero elements during training; at
+	// inference time every unit should be used, so we pass the input
+	// through unchanged.
+	if !isTraining {
+		return x, nil
+	}
+	return G.Dropout(x, d.probability)
+}
+
+func (d *Dropout) TrainableParameters() G.Nodes {
+	return nil
+}
+
+// Reshape changes the shape of its input without changing the underlying
+// data. This is used to flatten the output of the convolutional layers
+// before passing it into a Dense layer.
+type Reshape struct {
+	shape T.Shape
+}
+
+// NewReshape creates a layer that reshapes its input to `shape`.
+func NewReshape(shape ...int) *Reshape {
+	return &Reshape{shape: T.Shape(shape)}
+}
+
+func (r *Reshape) Forward(x *G.Node, isTraining bool) (*G.Node, error) {
+	return G.Reshape(x, r.shape)
+}
+
+func (r *Reshape) TrainableParameters() G.Nodes {
+	return nil
+}
+
+// Model strings together an ordered list of Layers to form a full network.
+// It plays the same role as the NeuralNetwork struct in the advanced XOR
+// example, but generalises over an arbitrary stack of layers instead of
+// hard-coding a single hidden layer.
+type Model struct {
+	g      *G.ExprGraph
+	layers []Layer
+
+	inputLayer  *G.Node
+	outputValue G.Value
+
+	targetOutputLayer *G.Node
+	lossValue         G.Value
+
+	machine G.VM
+}
+
+// NewModel builds a Model out of the given layers. g is the graph the
+// layers were constructed on (e.g. the one passed to NewConv2d/NewDense).
+// inputShape is the shape of a single batch of input data, e.g.
+// {batchSize, 1, 28, 28} for MNIST images. When isForTraining is true, the
+// target and loss nodes are added and gradients are wired up for every
+// layer's trainable parameters.
+func NewModel(g *G.ExprGraph, inputShape T.Shape, layers []Layer, numClasses int, isForTraining bool) (*Model, error) {
+	m := &Model{g: g, layers: layers}
+
+	m.inputLayer = G.NewTensor(m.g, G.Float64, len(inputShape), G.WithShape(inputShape...), G.WithName("input"))
+
+	x := m.inputLayer
+	for _, layer := range m.layers {
+		var err error
+		x, err = layer.Forward(x, isForTraining)
+		if err != nil {
+			return nil, fmt.Errorf("building layer forward pass: %w", err)
+		}
+	}
+
+	output, err := G.SoftMax(x)
+	if err != nil {
+		return nil, fmt.Errorf("adding softmax output: %w", err)
+	}
+	G.Read(output, &m.outputValue)
+
+	if isForTraining {
+		batchSize := inputShape[0]
+		m.targetOutputLayer = G.NewMatrix(m.g, G.Float64, G.WithShape(batchSize, numClasses), G.WithName("target"))
+
+		// Cross-entropy loss between the one-hot targets and the predicted
+		// class probabilities.
+		logProbs := G.Must(G.Log(output))
+		loss := G.Must(G.Neg(G.Must(G.Mean(G.Must(G.Sum(G.Must(G.HadamardProd(m.targetOutputLayer, logProbs)), 1))))))
+		G.Read(loss, &m.lossValue)
+
+		G.Grad(loss, m.getTrainableParameters()...)
+	}
+
+	m.machine = G.NewTapeMachine(m.g, G.BindDualValues(m.getTrainableParameters()...))
+
+	return m, nil
+}
+
+// CopyWeightsToModel copies every trainable parameter from m into target,
+// layer by layer. Both models must have been built from layer lists with
+// matching shapes (e.g. a training model and a prediction-only model built
+// from the same buildModel call), the same way NeuralNetwork.
+// CopyWeightsToModel works in the advanced XOR example.
+func (m *Model) CopyWeightsToModel(target *Model) error {
+	srcParams := m.getTrainableParameters()
+	dstParams := target.getTrainableParameters()
+	if len(srcParams) != len(dstParams) {
+		return fmt.Errorf("cannot copy weights: models have %d and %d trainable parameters", len(srcParams), len(dstParams))
+	}
+	for i := range srcParams {
+		if err := G.Let(dstParams[i], srcParams[i].Value()); err != nil {
+			return fmt.Errorf("copying parameter %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// getTrainableParameters collects the trainable nodes from every layer, in
+// the order the layers were added.
+func (m *Model) getTrainableParameters() G.Nodes {
+	var params G.Nodes
+	for _, layer := range m.layers {
+		params = append(params, layer.TrainableParameters()...)
+	}
+	return params
+}
+
+// FitBatch runs one forward and backward pass over a batch and updates the
+// weights with the given solver, returning the batch's loss.
+func (m *Model) FitBatch(inputs, targets T.Tensor, solver G.Solver) (float64, error) {
+	if m.targetOutputLayer == nil {
+		return 0, fmt.Errorf("cannot train a model that was not created for training")
+	}
+	m.machine.Reset()
+
+	if err := G.Let(m.inputLayer, inputs); err != nil {
+		return 0, err
+	}
+	if err := G.Let(m.targetOutputLayer, targets); err != nil {
+		return 0, err
+	}
+
+	if err := m.machine.RunAll(); err != nil {
+		return 0, fmt.Errorf("running forward/backward pass: %w", err)
+	}
+
+	if err := solver.Step(G.NodesToValueGrads(m.getTrainableParameters())); err != nil {
+		return 0, err
+	}
+
+	return m.lossValue.Data().(float64), nil
+}
+
+// Predict runs a forward-only pass over a batch and returns the predicted
+// class probabilities.
+func (m *Model) Predict(inputs T.Tensor) (T.Tensor, error) {
+	m.machine.Reset()
+
+	if err := G.Let(m.inputLayer, inputs); err != nil {
+		return nil, err
+	}
+
+	if err := m.machine.RunAll(); err != nil {
+		return nil, fmt.Errorf("running forward pass: %w", err)
+	}
+
+	return T.New(T.WithShape(m.outputValue.Shape()...), T.WithBacking(m.outputValue.Data())), nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+// TestModelShapePlumbing builds a small Conv2d/MaxPool2d/Reshape/Dense
+// stack (the same kind of layer list buildModel uses, just scaled down)
+// and checks that a forward pass produces the expected output shape. This
+// doesn't need real MNIST files on disk.
+func TestModelShapePlumbing(t *testing.T) {
+	const (
+		batchSize   = 2
+		numClasses  = 3
+		imageSize   = 8
+		inChannels  = 1
+		convOutput  = imageSize - 3 + 1 // kernel 3x3, no padding, stride 1
+		pooledSize  = convOutput / 2    // MaxPool2d(2)
+		flattenSize = 4 * pooledSize * pooledSize
+	)
+	inputShape := T.Shape{batchSize, inChannels, imageSize, imageSize}
+
+	g := G.NewGraph()
+	layers := []Layer{
+		NewConv2d(g, inChannels, 4, 3, 3),
+		NewMaxPool2d(2),
+		NewReshape(batchSize, flattenSize),
+		NewDense(g, flattenSize, numClasses),
+	}
+	model, err := NewModel(g, inputShape, layers, numClasses, false)
+	if err != nil {
+		t.Fatalf("NewModel failed: %v", err)
+	}
+
+	input := T.New(T.WithShape(inputShape...), T.WithBacking(make([]float64, inputShape.TotalSize())))
+	output, err := model.Predict(input)
+	if err != nil {
+		t.Fatalf("Predict failed: %v", err)
+	}
+
+	wantShape := T.Shape{batchSize, numClasses}
+	if !output.Shape().Eq(wantShape) {
+		t.Errorf("expected output shape %v, got %v", wantShape, output.Shape())
+	}
+}
+
+// TestDropoutDisabledDuringInference checks that Dropout.Forward is a
+// no-op when isTraining is false, which is what keeps Predict
+// deterministic on a model built with isForTraining=false.
+func TestDropoutDisabledDuringInference(t *testing.T) {
+	g := G.NewGraph()
+	x := G.NewMatrix(g, G.Float64, G.WithShape(1, 4))
+
+	dropout := NewDropout(0.5)
+	out, err := dropout.Forward(x, false)
+	if err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+	if out != x {
+		t.Errorf("expected Dropout.Forward to return the input node unchanged when isTraining is false")
+	}
+}
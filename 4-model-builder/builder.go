@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+
+	G "gorgonia.org/gorgonia"
+)
+
+// Activation identifies which activation function to apply after a Dense
+// layer's matrix multiply.
+type Activation int
+
+const (
+	Sigmoid Activation = iota
+	Tanh
+	ReLU
+	Softmax
+)
+
+func (a Activation) apply(x *G.Node) (*G.Node, error) {
+	switch a {
+	case Sigmoid:
+		return G.Sigmoid(x)
+	case Tanh:
+		return G.Tanh(x)
+	case ReLU:
+		return G.Rectify(x)
+	case Softmax:
+		return G.SoftMax(x)
+	default:
+		return nil, fmt.Errorf("unknown activation %v", a)
+	}
+}
+
+// Initializer identifies how a layer's weights should be randomly
+// initialized.
+type Initializer int
+
+const (
+	GlorotN Initializer = iota
+	GlorotU
+	HeN
+	Uniform
+)
+
+func (i Initializer) weightInit() G.InitWFn {
+	switch i {
+	case GlorotN:
+		return G.GlorotN(1.0)
+	case GlorotU:
+		return G.GlorotU(1.0)
+	case HeN:
+		return G.HeN(1.0)
+	case Uniform:
+		return G.Uniform(0.0, 1.0)
+	default:
+		panic(fmt.Sprintf("unknown initializer %v", i))
+	}
+}
+
+// Loss identifies how the output layer should be compared against the
+// target to produce a scalar loss node.
+type Loss int
+
+const (
+	MSE Loss = iota
+	CrossEntropy
+	BinaryCrossEntropy
+)
+
+func (l Loss) apply(output, target *G.Node) (*G.Node, error) {
+	switch l {
+	case MSE:
+		return G.Mean(G.Must(G.Square(G.Must(G.Sub(output, target)))))
+	case CrossEntropy:
+		logProbs := G.Must(G.Log(output))
+		return G.Neg(G.Must(G.Mean(G.Must(G.Sum(G.Must(G.HadamardProd(target, logProbs)), 1)))))
+	case BinaryCrossEntropy:
+		// G.BinaryXent computes -(y*log(p) + (1-y)*log(1-p)) element-wise,
+		// which is what makes this "binary" as opposed to the categorical
+		// CrossEntropy case above.
+		return G.Mean(G.Must(G.BinaryXent(output, target)))
+	default:
+		return nil, fmt.Errorf("unknown loss %v", l)
+	}
+}
+
+// denseSpec describes one Dense layer before the graph has been built.
+type denseSpec struct {
+	size       int
+	activation Activation
+}
+
+// ModelBuilder accumulates a description of a feed-forward network one
+// layer at a time, then builds the gorgonia graph for it in one go. This
+// replaces hand-writing the forward pass and G.Grad call for every new
+// topology, like the advanced XOR example does.
+type ModelBuilder struct {
+	inputSize   int
+	layers      []denseSpec
+	initializer Initializer
+	loss        Loss
+}
+
+// NewModelBuilder starts a new builder with sensible defaults: GlorotN
+// initialization and mean squared error loss.
+func NewModelBuilder() *ModelBuilder {
+	return &ModelBuilder{
+		initializer: GlorotN,
+		loss:        MSE,
+	}
+}
+
+// Input sets the number of features in a single sample. This must be
+// called before BuildTraining or BuildPrediction.
+func (b *ModelBuilder) Input(size int) *ModelBuilder {
+	b.inputSize = size
+	return b
+}
+
+// Dense appends a fully connected layer with `size` outputs, followed by
+// the given activation.
+func (b *ModelBuilder) Dense(size int, activation Activation) *ModelBuilder {
+	b.layers = append(b.layers, denseSpec{size: size, activation: activation})
+	return b
+}
+
+// Initializer overrides the default weight initializer used for every
+// layer in the network.
+func (b *ModelBuilder) Initializer(initializer Initializer) *ModelBuilder {
+	b.initializer = initializer
+	return b
+}
+
+// Loss overrides the default loss function used when building a training
+// model.
+func (b *ModelBuilder) Loss(loss Loss) *ModelBuilder {
+	b.loss = loss
+	return b
+}
+
+// BuildTraining constructs a Model with the accumulated layers, wired up
+// for training on batches of size batchSize: a target node, a loss node,
+// and gradients with respect to every layer's weights and biases.
+func (b *ModelBuilder) BuildTraining(batchSize int) (*Model, error) {
+	return b.build(batchSize, true)
+}
+
+// BuildPrediction constructs a Model with the accumulated layers, wired up
+// only for inference on batches of size batchSize (no target or loss
+// nodes).
+func (b *ModelBuilder) BuildPrediction(batchSize int) (*Model, error) {
+	return b.build(batchSize, false)
+}
+
+func (b *ModelBuilder) build(batchSize int, isForTraining bool) (*Model, error) {
+	if b.inputSize == 0 {
+		return nil, fmt.Errorf("model builder: Input must be called before building")
+	}
+	if len(b.layers) == 0 {
+		return nil, fmt.Errorf("model builder: at least one Dense layer is required")
+	}
+
+	m := &Model{}
+	m.g = G.NewGraph()
+	m.inputLayer = G.NewMatrix(m.g, G.Float64, G.WithShape(batchSize, b.inputSize))
+
+	x := m.inputLayer
+	prevSize := b.inputSize
+	for i, layer := range b.layers {
+		weights := G.NewMatrix(m.g, G.Float64, G.WithShape(prevSize, layer.size), G.WithInit(b.initializer.weightInit()), G.WithName(fmt.Sprintf("dense_w_%d", i)))
+		bias := G.NewVector(m.g, G.Float64, G.WithShape(layer.size), G.WithInit(G.Zeroes()), G.WithName(fmt.Sprintf("dense_b_%d", i)))
+
+		xw, err := G.Mul(x, weights)
+		if err != nil {
+			return nil, fmt.Errorf("building layer %d: %w", i, err)
+		}
+		xwb, err := G.BroadcastAdd(xw, bias, nil, []byte{0})
+		if err != nil {
+			return nil, fmt.Errorf("building layer %d: %w", i, err)
+		}
+		x, err = layer.activation.apply(xwb)
+		if err != nil {
+			return nil, fmt.Errorf("building layer %d: %w", i, err)
+		}
+
+		m.weights = append(m.weights, weights)
+		m.biases = append(m.biases, bias)
+		prevSize = layer.size
+	}
+
+	G.Read(x, &m.outputValue)
+
+	if isForTraining {
+		m.targetOutputLayer = G.NewMatrix(m.g, G.Float64, G.WithShape(batchSize, prevSize))
+
+		loss, err := b.loss.apply(x, m.targetOutputLayer)
+		if err != nil {
+			return nil, fmt.Errorf("building loss: %w", err)
+		}
+		G.Read(loss, &m.lossValue)
+
+		G.Grad(loss, m.getTrainableParameters()...)
+	}
+
+	m.machine = G.NewTapeMachine(m.g, G.BindDualValues(m.getTrainableParameters()...))
+
+	return m, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+// TestBinaryCrossEntropyLossTrains exercises the BinaryCrossEntropy branch
+// of Loss.apply, which previously shared (wrong) code with CrossEntropy.
+func TestBinaryCrossEntropyLossTrains(t *testing.T) {
+	x := T.New(
+		T.WithShape(4, 2),
+		T.WithBacking([]float64{0, 0, 0, 1, 1, 0, 1, 1}),
+	)
+	y := T.New(
+		T.WithShape(4, 1),
+		T.WithBacking([]float64{0, 1, 1, 0}),
+	)
+
+	model, err := NewModelBuilder().
+		Input(2).
+		Dense(5, Sigmoid).
+		Dense(1, Sigmoid).
+		Loss(BinaryCrossEntropy).
+		BuildTraining(4)
+	if err != nil {
+		t.Fatalf("BuildTraining failed: %v", err)
+	}
+
+	solver := G.NewAdamSolver(G.WithLearnRate(0.05))
+	var firstLoss, lastLoss float64
+	for epoch := 0; epoch < 200; epoch++ {
+		loss, err := model.FitBatch(x, y, solver)
+		if err != nil {
+			t.Fatalf("FitBatch failed: %v", err)
+		}
+		if epoch == 0 {
+			firstLoss = loss
+		}
+		lastLoss = loss
+	}
+
+	if lastLoss >= firstLoss {
+		t.Errorf("expected binary cross-entropy loss to decrease, went from %.4f to %.4f", firstLoss, lastLoss)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"testing"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+// TestEvaluateClassificationMetrics pins a single Dense+Softmax layer's
+// weights to a scaled identity matrix, so the predicted class is simply
+// the argmax of the input. This lets us plant a known mix of correct and
+// incorrect predictions and check that ValAccuracy and ConfusionMatrix
+// come out exactly right, rather than just "trending the right way".
+func TestEvaluateClassificationMetrics(t *testing.T) {
+	const (
+		batchSize  = 4
+		numClasses = 3
+	)
+
+	model, err := NewModelBuilder().
+		Input(numClasses).
+		Dense(numClasses, Softmax).
+		BuildTraining(batchSize)
+	if err != nil {
+		t.Fatalf("BuildTraining failed: %v", err)
+	}
+
+	// A large scaled identity matrix means softmax(x*W) preserves the
+	// argmax of x, so we know exactly which class each sample predicts.
+	identity := T.New(T.WithShape(numClasses, numClasses), T.WithBacking([]float64{
+		10, 0, 0,
+		0, 10, 0,
+		0, 0, 10,
+	}))
+	if err := G.Let(model.weights[0], identity); err != nil {
+		t.Fatalf("setting weights failed: %v", err)
+	}
+
+	// Samples 0-2 predict classes 0, 1, 2 (matching their input's argmax)
+	// and are given matching targets. Sample 3 also has input argmax 0,
+	// but is deliberately given target class 1, planting one wrong
+	// prediction with a known confusion-matrix cell.
+	x := T.New(T.WithShape(batchSize, numClasses), T.WithBacking([]float64{
+		5, 0, 0,
+		0, 5, 0,
+		0, 0, 5,
+		5, 0, 0,
+	}))
+	y := T.New(T.WithShape(batchSize, numClasses), T.WithBacking([]float64{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+		0, 1, 0,
+	}))
+
+	metrics, err := model.evaluate(x, y, []int{0, 1, 2, 3}, batchSize, numClasses)
+	if err != nil {
+		t.Fatalf("evaluate failed: %v", err)
+	}
+
+	if metrics.ValAccuracy != 0.75 {
+		t.Errorf("expected accuracy 0.75 (3/4 correct), got %v", metrics.ValAccuracy)
+	}
+
+	// Confusion matrix is indexed [actual][predicted]: three samples are
+	// correctly predicted on the diagonal, and the planted sample 3 is
+	// actual class 1 but predicted class 0.
+	want := [][]int{
+		{1, 0, 0},
+		{1, 1, 0},
+		{0, 0, 1},
+	}
+	if len(metrics.ConfusionMatrix) != len(want) {
+		t.Fatalf("expected a %dx%d confusion matrix, got %d rows", len(want), len(want), len(metrics.ConfusionMatrix))
+	}
+	for i := range want {
+		for j := range want[i] {
+			if metrics.ConfusionMatrix[i][j] != want[i][j] {
+				t.Errorf("confusion[%d][%d] = %d, want %d", i, j, metrics.ConfusionMatrix[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+// TestFitEarlyStopping checks that Fit stops before opts.Epochs once the
+// validation loss fails to improve for EarlyStoppingPatience epochs. A
+// zero learning rate means the weights never change, so the validation
+// loss is identical every epoch and patience should trigger immediately.
+func TestFitEarlyStopping(t *testing.T) {
+	const batchSize = 2
+	model, err := NewModelBuilder().
+		Input(2).
+		Dense(1, Sigmoid).
+		BuildTraining(batchSize)
+	if err != nil {
+		t.Fatalf("BuildTraining failed: %v", err)
+	}
+
+	x := T.New(T.WithShape(8, 2), T.WithBacking([]float64{
+		0, 0, 0, 1, 1, 0, 1, 1,
+		0, 0, 0, 1, 1, 0, 1, 1,
+	}))
+	y := T.New(T.WithShape(8, 1), T.WithBacking([]float64{0, 1, 1, 0, 0, 1, 1, 0}))
+
+	solver := G.NewAdamSolver(G.WithLearnRate(0))
+	history, err := model.Fit(x, y, FitOptions{
+		Epochs:                10,
+		BatchSize:             batchSize,
+		ValidationSplit:       0.25,
+		ShuffleSeed:           1,
+		EarlyStoppingPatience: 1,
+	}, solver)
+	if err != nil {
+		t.Fatalf("Fit failed: %v", err)
+	}
+
+	if len(history.Epochs) >= 10 {
+		t.Errorf("expected early stopping to cut training short of 10 epochs, ran %d", len(history.Epochs))
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+// Model is the graph produced by a ModelBuilder: an ordered stack of Dense
+// layers plus, when built for training, the target and loss nodes needed
+// to run backpropagation.
+type Model struct {
+	g           *G.ExprGraph
+	inputLayer  *G.Node
+	weights     []*G.Node
+	biases      []*G.Node
+	outputValue G.Value
+
+	targetOutputLayer *G.Node
+	lossValue         G.Value
+
+	machine G.VM
+}
+
+// getTrainableParameters returns every layer's weights and biases, in the
+// order the layers were added.
+func (m *Model) getTrainableParameters() G.Nodes {
+	params := make(G.Nodes, 0, len(m.weights)+len(m.biases))
+	for i := range m.weights {
+		params = append(params, m.weights[i], m.biases[i])
+	}
+	return params
+}
+
+// FitBatch runs one forward and backward pass over a batch and updates the
+// weights with the given solver, returning the batch's loss. The model
+// must have been built with BuildTraining.
+func (m *Model) FitBatch(inputs, targets T.Tensor, solver G.Solver) (float64, error) {
+	if m.targetOutputLayer == nil {
+		return 0, fmt.Errorf("cannot train a model that was not built with BuildTraining")
+	}
+	m.machine.Reset()
+
+	if err := G.Let(m.inputLayer, inputs); err != nil {
+		return 0, err
+	}
+	if err := G.Let(m.targetOutputLayer, targets); err != nil {
+		return 0, err
+	}
+
+	if err := m.machine.RunAll(); err != nil {
+		return 0, fmt.Errorf("running forward/backward pass: %w", err)
+	}
+
+	if err := solver.Step(G.NodesToValueGrads(m.getTrainableParameters())); err != nil {
+		return 0, err
+	}
+
+	return m.lossValue.Data().(float64), nil
+}
+
+// Predict runs the model over a batch and returns the model's output. On a
+// model built with BuildTraining the tape also holds the loss and gradient
+// ops, so those run too (against the last target set), but no weights are
+// updated.
+func (m *Model) Predict(inputs T.Tensor) (T.Tensor, error) {
+	m.machine.Reset()
+
+	if err := G.Let(m.inputLayer, inputs); err != nil {
+		return nil, err
+	}
+
+	if err := m.machine.RunAll(); err != nil {
+		return nil, fmt.Errorf("running forward pass: %w", err)
+	}
+
+	return T.New(T.WithShape(m.outputValue.Shape()...), T.WithBacking(m.outputValue.Data())), nil
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+// FitOptions configures a call to Model.Fit.
+type FitOptions struct {
+	// Epochs is how many times to iterate over the training split.
+	Epochs int
+	// BatchSize must match the batch size the Model was built with, since
+	// Fit reuses the Model's pre-compiled TapeMachine rather than
+	// recompiling the graph for every batch.
+	BatchSize int
+	// ValidationSplit is the fraction (0, 1) of samples held out for
+	// validation at the end of each epoch.
+	ValidationSplit float64
+	// ShuffleSeed seeds the per-epoch shuffle of the training indices, so
+	// a run can be reproduced.
+	ShuffleSeed int64
+	// EarlyStoppingPatience stops training early if the validation loss
+	// hasn't improved for this many epochs. Zero disables early stopping.
+	EarlyStoppingPatience int
+	// ClassificationClasses, if non-zero, enables accuracy and confusion
+	// matrix metrics, computed by comparing the argmax of the output and
+	// target vectors for each validation sample.
+	ClassificationClasses int
+}
+
+// EpochMetrics holds the metrics collected for a single epoch of Fit.
+type EpochMetrics struct {
+	TrainLoss float64
+	ValLoss   float64
+	// ValAccuracy and ConfusionMatrix are only populated when
+	// FitOptions.ClassificationClasses is non-zero.
+	ValAccuracy     float64
+	ConfusionMatrix [][]int
+}
+
+// History is the result of a call to Fit: one EpochMetrics per epoch that
+// was actually run.
+type History struct {
+	Epochs []EpochMetrics
+}
+
+// Fit trains the model over multiple epochs of mini-batches, shuffling
+// the training split each epoch, holding out a validation split, and
+// recording per-epoch metrics. The model must have been built with
+// BuildTraining and a batch size matching opts.BatchSize.
+func (m *Model) Fit(x, y T.Tensor, opts FitOptions, solver G.Solver) (*History, error) {
+	numSamples := x.Shape()[0]
+	numVal := int(float64(numSamples) * opts.ValidationSplit)
+	numTrain := numSamples - numVal
+
+	if numTrain < opts.BatchSize {
+		return nil, fmt.Errorf("training split (%d samples) is smaller than the batch size (%d)", numTrain, opts.BatchSize)
+	}
+
+	trainIdx := make([]int, numTrain)
+	for i := range trainIdx {
+		trainIdx[i] = i
+	}
+	valIdx := make([]int, numVal)
+	for i := range valIdx {
+		valIdx[i] = numTrain + i
+	}
+
+	rng := rand.New(rand.NewSource(opts.ShuffleSeed))
+
+	history := &History{}
+	bestValLoss := 0.0
+	epochsSinceImprovement := 0
+
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		rng.Shuffle(len(trainIdx), func(i, j int) { trainIdx[i], trainIdx[j] = trainIdx[j], trainIdx[i] })
+
+		var trainLossSum float64
+		numBatches := len(trainIdx) / opts.BatchSize
+		for b := 0; b < numBatches; b++ {
+			batchIdx := trainIdx[b*opts.BatchSize : (b+1)*opts.BatchSize]
+
+			xBatch, err := selectRows(x, batchIdx)
+			if err != nil {
+				return nil, fmt.Errorf("selecting training batch: %w", err)
+			}
+			yBatch, err := selectRows(y, batchIdx)
+			if err != nil {
+				return nil, fmt.Errorf("selecting training batch: %w", err)
+			}
+
+			loss, err := m.FitBatch(xBatch, yBatch, solver)
+			if err != nil {
+				return nil, fmt.Errorf("fitting batch %d of epoch %d: %w", b, epoch, err)
+			}
+			trainLossSum += loss
+		}
+
+		metrics := EpochMetrics{TrainLoss: trainLossSum / float64(numBatches)}
+		if numVal > 0 {
+			valMetrics, err := m.evaluate(x, y, valIdx, opts.BatchSize, opts.ClassificationClasses)
+			if err != nil {
+				return nil, fmt.Errorf("evaluating validation split for epoch %d: %w", epoch, err)
+			}
+			metrics.ValLoss = valMetrics.ValLoss
+			metrics.ValAccuracy = valMetrics.ValAccuracy
+			metrics.ConfusionMatrix = valMetrics.ConfusionMatrix
+		}
+		history.Epochs = append(history.Epochs, metrics)
+
+		if opts.EarlyStoppingPatience > 0 && numVal > 0 {
+			if epoch == 0 || metrics.ValLoss < bestValLoss {
+				bestValLoss = metrics.ValLoss
+				epochsSinceImprovement = 0
+			} else {
+				epochsSinceImprovement++
+				if epochsSinceImprovement >= opts.EarlyStoppingPatience {
+					break
+				}
+			}
+		}
+	}
+
+	return history, nil
+}
+
+// evaluate runs the model over idx in chunks of batchSize, averaging the
+// loss and, when numClasses is non-zero, computing accuracy and a
+// confusion matrix from the argmax of the output and target vectors.
+// Samples that don't fill a full final batch are dropped, since the
+// model's TapeMachine is compiled for a fixed batch size.
+func (m *Model) evaluate(x, y T.Tensor, idx []int, batchSize, numClasses int) (EpochMetrics, error) {
+	var confusion [][]int
+	if numClasses > 0 {
+		confusion = make([][]int, numClasses)
+		for i := range confusion {
+			confusion[i] = make([]int, numClasses)
+		}
+	}
+
+	var lossSum float64
+	var correct, total int
+	numBatches := len(idx) / batchSize
+	for b := 0; b < numBatches; b++ {
+		batchIdx := idx[b*batchSize : (b+1)*batchSize]
+
+		xBatch, err := selectRows(x, batchIdx)
+		if err != nil {
+			return EpochMetrics{}, err
+		}
+		yBatch, err := selectRows(y, batchIdx)
+		if err != nil {
+			return EpochMetrics{}, err
+		}
+
+		m.machine.Reset()
+		if err := G.Let(m.inputLayer, xBatch); err != nil {
+			return EpochMetrics{}, err
+		}
+		if err := G.Let(m.targetOutputLayer, yBatch); err != nil {
+			return EpochMetrics{}, err
+		}
+		if err := m.machine.RunAll(); err != nil {
+			return EpochMetrics{}, fmt.Errorf("running forward pass: %w", err)
+		}
+		lossSum += m.lossValue.Data().(float64)
+
+		if numClasses > 0 {
+			output := m.outputValue.Data().([]float64)
+			target := yBatch.Data().([]float64)
+			for sample := 0; sample < batchSize; sample++ {
+				predicted := argmax(output[sample*numClasses : (sample+1)*numClasses])
+				actual := argmax(target[sample*numClasses : (sample+1)*numClasses])
+				confusion[actual][predicted]++
+				if predicted == actual {
+					correct++
+				}
+				total++
+			}
+		}
+	}
+
+	metrics := EpochMetrics{ValLoss: lossSum / float64(numBatches)}
+	if numClasses > 0 {
+		metrics.ValAccuracy = float64(correct) / float64(total)
+		metrics.ConfusionMatrix = confusion
+	}
+	return metrics, nil
+}
+
+// selectRows builds a new tensor containing only the rows of t at the
+// given indices, in order. This is used to both shuffle and slice mini-
+// batches from a full dataset tensor.
+func selectRows(t T.Tensor, rows []int) (T.Tensor, error) {
+	shape := t.Shape()
+	rowWidth := 1
+	for _, dim := range shape[1:] {
+		rowWidth *= dim
+	}
+
+	data, ok := t.Data().([]float64)
+	if !ok {
+		return nil, fmt.Errorf("selectRows only supports float64 tensors")
+	}
+
+	backing := make([]float64, len(rows)*rowWidth)
+	for i, row := range rows {
+		copy(backing[i*rowWidth:(i+1)*rowWidth], data[row*rowWidth:(row+1)*rowWidth])
+	}
+
+	newShape := append([]int{len(rows)}, shape[1:]...)
+	return T.New(T.WithShape(newShape...), T.WithBacking(backing)), nil
+}
+
+// argmax returns the index of the largest value in v.
+func argmax(v []float64) int {
+	best := 0
+	for i, value := range v {
+		if value > v[best] {
+			best = i
+		}
+	}
+	return best
+}
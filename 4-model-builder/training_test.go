@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	G "gorgonia.org/gorgonia"
+	T "gorgonia.org/tensor"
+)
+
+// TestFitShuffledMiniBatches trains on a dataset large enough to need
+// multiple mini-batches and a validation split, and checks that the
+// training loss generally decreases and that History reports one
+// EpochMetrics per epoch.
+func TestFitShuffledMiniBatches(t *testing.T) {
+	// Repeat the XOR samples enough times to have a dataset that can be
+	// split into training/validation and still fill several batches.
+	const repeats = 25
+	xRaw := make([]float64, 0, repeats*4*2)
+	yRaw := make([]float64, 0, repeats*4)
+	samplesX := [][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	samplesY := []float64{0, 1, 1, 0}
+	for i := 0; i < repeats; i++ {
+		for s, sample := range samplesX {
+			xRaw = append(xRaw, sample...)
+			yRaw = append(yRaw, samplesY[s])
+		}
+	}
+	x := T.New(T.WithShape(repeats*4, 2), T.WithBacking(xRaw))
+	y := T.New(T.WithShape(repeats*4, 1), T.WithBacking(yRaw))
+
+	const batchSize = 10
+	model, err := NewModelBuilder().
+		Input(2).
+		Dense(5, Sigmoid).
+		Dense(1, Sigmoid).
+		BuildTraining(batchSize)
+	if err != nil {
+		t.Fatalf("BuildTraining failed: %v", err)
+	}
+
+	solver := G.NewAdamSolver(G.WithLearnRate(0.05))
+	history, err := model.Fit(x, y, FitOptions{
+		Epochs:          20,
+		BatchSize:       batchSize,
+		ValidationSplit: 0.2,
+		ShuffleSeed:     42,
+	}, solver)
+	if err != nil {
+		t.Fatalf("Fit failed: %v", err)
+	}
+
+	if len(history.Epochs) != 20 {
+		t.Fatalf("expected 20 epochs of history, got %d", len(history.Epochs))
+	}
+
+	firstLoss := history.Epochs[0].TrainLoss
+	lastLoss := history.Epochs[len(history.Epochs)-1].TrainLoss
+	if lastLoss >= firstLoss {
+		t.Errorf("expected training loss to decrease, went from %.4f to %.4f", firstLoss, lastLoss)
+	}
+}
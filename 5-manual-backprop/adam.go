@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Adam is a from-scratch implementation of the Adam optimizer, mirroring
+// what G.NewAdamSolver does inside Gorgonia in the other tutorials, but
+// with the moment estimates and bias correction written out explicitly.
+type Adam struct {
+	LearnRate float64
+	Beta1     float64
+	Beta2     float64
+	Epsilon   float64
+
+	t int
+
+	mWeights, vWeights []*mat.Dense
+	mBiases, vBiases   []*mat.Dense
+}
+
+// NewAdam creates an Adam optimizer with the given learning rate and the
+// commonly used defaults for the other hyperparameters.
+func NewAdam(learnRate float64) *Adam {
+	return &Adam{
+		LearnRate: learnRate,
+		Beta1:     0.9,
+		Beta2:     0.999,
+		Epsilon:   1e-8,
+	}
+}
+
+// Step applies one Adam update to every weight and bias in n, given the
+// gradients computed by NeuralNetwork.Backward.
+func (a *Adam) Step(n *NeuralNetwork, gradWeights, gradBiases []*mat.Dense) {
+	if a.mWeights == nil {
+		a.mWeights = zerosLike(gradWeights)
+		a.vWeights = zerosLike(gradWeights)
+		a.mBiases = zerosLike(gradBiases)
+		a.vBiases = zerosLike(gradBiases)
+	}
+
+	a.t++
+	biasCorrection1 := 1 - math.Pow(a.Beta1, float64(a.t))
+	biasCorrection2 := 1 - math.Pow(a.Beta2, float64(a.t))
+
+	for l := range n.Weights {
+		a.update(n.Weights[l], gradWeights[l], a.mWeights[l], a.vWeights[l], biasCorrection1, biasCorrection2)
+		a.update(n.Biases[l], gradBiases[l], a.mBiases[l], a.vBiases[l], biasCorrection1, biasCorrection2)
+	}
+}
+
+// update applies the Adam rule to a single weight or bias matrix in
+// place, using and updating its accompanying first (m) and second (v)
+// moment matrices.
+func (a *Adam) update(param, grad, m, v *mat.Dense, biasCorrection1, biasCorrection2 float64) {
+	rows, cols := param.Dims()
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			g := grad.At(i, j)
+
+			mVal := a.Beta1*m.At(i, j) + (1-a.Beta1)*g
+			vVal := a.Beta2*v.At(i, j) + (1-a.Beta2)*g*g
+			m.Set(i, j, mVal)
+			v.Set(i, j, vVal)
+
+			mHat := mVal / biasCorrection1
+			vHat := vVal / biasCorrection2
+
+			param.Set(i, j, param.At(i, j)-a.LearnRate*mHat/(math.Sqrt(vHat)+a.Epsilon))
+		}
+	}
+}
+
+// zerosLike returns a slice of zero-filled matrices with the same shapes
+// as the matrices in src.
+func zerosLike(src []*mat.Dense) []*mat.Dense {
+	out := make([]*mat.Dense, len(src))
+	for i, m := range src {
+		rows, cols := m.Dims()
+		out[i] = mat.NewDense(rows, cols, make([]float64, rows*cols))
+	}
+	return out
+}
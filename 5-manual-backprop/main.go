@@ -0,0 +1,74 @@
+package main
+
+/*
+Manual Backpropagation Example by Josh Pattman
+
+Every other tutorial in this repo builds its network as a Gorgonia graph
+and lets `G.Grad` work out the gradients for us. This tutorial trains the
+exact same XOR network, but with the forward pass, backward pass, and
+Adam update all written out by hand with gonum matrices instead. There is
+no computation graph here - just plain Go and linear algebra. Reading
+this alongside the advanced XOR example is a good way to understand what
+Gorgonia is actually doing for you.
+
+Here is some version info about my setup (other versions may work):
+* `go 1.20.2`
+* `gonum.org/v1/gonum v0.13.0`
+*/
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func main() {
+	// ------------------------ Create the dataset ------------------------
+	xRaw := [][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	yRaw := []float64{0, 1, 1, 0}
+
+	// ------------------------ Create the neural net ------------------------
+	// Same topology as the advanced XOR example: 2 inputs, 5 hidden
+	// neurons, 1 output.
+	network := NewNeuralNetwork([]int{2, 5, 1})
+	solver := NewAdam(0.05)
+
+	// ------------------------ Train the neural net ------------------------
+	// Gorgonia trains on the whole dataset as a single batched matrix
+	// multiply. Here we keep it simple and loop over one sample at a
+	// time, accumulating gradients before applying the Adam update.
+	for epoch := 0; epoch < 1000; epoch++ {
+		var epochLoss float64
+		gradWeights := zerosLike(network.Weights)
+		gradBiases := zerosLike(network.Biases)
+
+		for i, x := range xRaw {
+			input := mat.NewDense(2, 1, x)
+			target := mat.NewDense(1, 1, []float64{yRaw[i]})
+
+			sampleGradWeights, sampleGradBiases, loss := network.Backward(input, target)
+			for l := range gradWeights {
+				gradWeights[l].Add(gradWeights[l], sampleGradWeights[l])
+				gradBiases[l].Add(gradBiases[l], sampleGradBiases[l])
+			}
+			epochLoss += loss
+		}
+
+		numSamples := float64(len(xRaw))
+		for l := range gradWeights {
+			gradWeights[l].Scale(1/numSamples, gradWeights[l])
+			gradBiases[l].Scale(1/numSamples, gradBiases[l])
+		}
+		solver.Step(network, gradWeights, gradBiases)
+
+		fmt.Printf("Epoch: %d, Loss: %.3f\n", epoch, epochLoss/numSamples)
+	}
+
+	// ------------------------ Test the neural net ------------------------
+	fmt.Println("\nPredictions:")
+	for i, x := range xRaw {
+		input := mat.NewDense(2, 1, x)
+		prediction := network.Predict(input)
+		fmt.Printf("X: %v, Y: %v, YP: %.2f\n", x, yRaw[i], prediction.At(0, 0))
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// NeuralNetwork is a plain feed-forward network with sigmoid activations,
+// implemented with nothing but gonum matrices. Unlike the other tutorials
+// in this repo, there is no Gorgonia graph here: every forward pass,
+// gradient, and weight update is written out by hand so you can see what a
+// library like Gorgonia is actually doing under the hood.
+type NeuralNetwork struct {
+	// Weights[l] maps the activations of layer l to the pre-activations of
+	// layer l+1, so it has shape (layerSizes[l+1], layerSizes[l]).
+	Weights []*mat.Dense
+	// Biases[l] has shape (layerSizes[l+1], 1).
+	Biases []*mat.Dense
+}
+
+// NewNeuralNetwork creates a network with the given layer sizes, e.g.
+// {2, 5, 1} for a network with 2 inputs, one hidden layer of 5 neurons,
+// and 1 output. Weights are initialized with small random values.
+func NewNeuralNetwork(layerSizes []int) *NeuralNetwork {
+	n := &NeuralNetwork{}
+	for l := 0; l < len(layerSizes)-1; l++ {
+		in, out := layerSizes[l], layerSizes[l+1]
+
+		weights := mat.NewDense(out, in, randomBacking(out*in))
+		biases := mat.NewDense(out, 1, make([]float64, out))
+
+		n.Weights = append(n.Weights, weights)
+		n.Biases = append(n.Biases, biases)
+	}
+	return n
+}
+
+// randomBacking returns n small random values, suitable for initializing
+// a layer's weights.
+func randomBacking(n int) []float64 {
+	backing := make([]float64, n)
+	for i := range backing {
+		backing[i] = rand.NormFloat64() * 0.5
+	}
+	return backing
+}
+
+// forward runs input through every layer, returning the pre-activations
+// (zs) and activations (including the input as activations[0]) of every
+// layer. These are needed by Backward to compute gradients via the chain
+// rule.
+func (n *NeuralNetwork) forward(input *mat.Dense) (zs, activations []*mat.Dense) {
+	activations = []*mat.Dense{input}
+	a := input
+	for l := range n.Weights {
+		var z mat.Dense
+		z.Mul(n.Weights[l], a)
+		z.Add(&z, n.Biases[l])
+
+		var activated mat.Dense
+		activated.Apply(func(_, _ int, v float64) float64 { return sigmoid(v) }, &z)
+
+		zs = append(zs, &z)
+		activations = append(activations, &activated)
+		a = &activated
+	}
+	return zs, activations
+}
+
+// Predict runs a single sample through the network and returns its
+// output activation.
+func (n *NeuralNetwork) Predict(input *mat.Dense) *mat.Dense {
+	_, activations := n.forward(input)
+	return activations[len(activations)-1]
+}
+
+// Backward computes, for a single sample, the mean squared error loss and
+// the gradient of the quadratic cost C = ½·Σ(a_L - y)² with respect to
+// every weight and bias matrix (for one output, that is half the gradient
+// of the returned loss), using the standard backpropagation chain rule:
+//
+//	delta_L = (a_L - y) ⊙ sigmoid'(z_L)
+//	delta_l = (W_(l+1)^T · delta_(l+1)) ⊙ sigmoid'(z_l)
+//	dC/dW_l = delta_l · a_(l-1)^T
+//	dC/db_l = delta_l
+func (n *NeuralNetwork) Backward(input, target *mat.Dense) (gradWeights, gradBiases []*mat.Dense, loss float64) {
+	numLayers := len(n.Weights)
+	zs, activations := n.forward(input)
+
+	gradWeights = make([]*mat.Dense, numLayers)
+	gradBiases = make([]*mat.Dense, numLayers)
+
+	output := activations[numLayers]
+	var errorVec mat.Dense
+	errorVec.Sub(output, target)
+	loss = meanSquare(&errorVec)
+
+	// delta_L = (a_L - y) ⊙ sigmoid'(z_L)
+	delta := hadamardSigmoidPrime(&errorVec, zs[numLayers-1])
+
+	for l := numLayers - 1; l >= 0; l-- {
+		var gradW mat.Dense
+		gradW.Mul(delta, activations[l].T())
+		gradWeights[l] = &gradW
+		gradBiases[l] = delta
+
+		if l > 0 {
+			var propagated mat.Dense
+			propagated.Mul(n.Weights[l].T(), delta)
+			delta = hadamardSigmoidPrime(&propagated, zs[l-1])
+		}
+	}
+
+	return gradWeights, gradBiases, loss
+}
+
+func sigmoid(v float64) float64 {
+	return 1 / (1 + math.Exp(-v))
+}
+
+func sigmoidPrime(v float64) float64 {
+	s := sigmoid(v)
+	return s * (1 - s)
+}
+
+// hadamardSigmoidPrime returns a ⊙ sigmoid'(z), element-wise.
+func hadamardSigmoidPrime(a, z *mat.Dense) *mat.Dense {
+	var result mat.Dense
+	result.Apply(func(i, j int, v float64) float64 { return v * sigmoidPrime(z.At(i, j)) }, a)
+	return &result
+}
+
+// meanSquare returns the mean of the squares of every element in m.
+func meanSquare(m *mat.Dense) float64 {
+	rows, cols := m.Dims()
+	var sum float64
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			v := m.At(i, j)
+			sum += v * v
+		}
+	}
+	return sum / float64(rows*cols)
+}